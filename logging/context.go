@@ -0,0 +1,53 @@
+package logging
+
+import "context"
+
+type loggerCtxKeyType struct{}
+type fieldsCtxKeyType struct{}
+
+var (
+	loggerCtxKey = loggerCtxKeyType{}
+	fieldsCtxKey = fieldsCtxKeyType{}
+)
+
+// WithLogger returns a copy of ctx carrying l, retrievable with
+// FromContext, so handlers don't have to hand-thread a logger through
+// every call signature.
+func WithLogger(ctx context.Context, l Logger) context.Context {
+	return context.WithValue(ctx, loggerCtxKey, l)
+}
+
+// WithFields returns a copy of ctx with fields merged on top of any
+// already stashed by a previous WithFields call. FromContext applies
+// these on top of the logger's own base fields, so request-scoped data
+// (trace id, user id, ...) doesn't need the logger rebuilt to carry it.
+func WithFields(ctx context.Context, fields Fields) context.Context {
+	merged := make(Fields, len(fields)+4)
+	for k, v := range fieldsFromContext(ctx) {
+		merged[k] = v
+	}
+	for k, v := range fields {
+		merged[k] = v
+	}
+	return context.WithValue(ctx, fieldsCtxKey, merged)
+}
+
+func fieldsFromContext(ctx context.Context) Fields {
+	f, _ := ctx.Value(fieldsCtxKey).(Fields)
+	return f
+}
+
+// FromContext returns the Logger stashed in ctx by WithLogger, with any
+// fields stashed by WithFields merged on top. It returns nil if ctx
+// carries no logger; callers on a request path should typically fall
+// back to a registry's root logger in that case.
+func FromContext(ctx context.Context) Logger {
+	l, _ := ctx.Value(loggerCtxKey).(Logger)
+	if l == nil {
+		return nil
+	}
+	if fields := fieldsFromContext(ctx); len(fields) > 0 {
+		return l.With(fields)
+	}
+	return l
+}
@@ -0,0 +1,151 @@
+package logging
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/spf13/viper"
+)
+
+// Fields is a set of key/value pairs attached to every entry a logger
+// writes. It exists so callers and backends don't have to depend on
+// logrus.Fields (or any other backend-specific type) directly.
+type Fields map[string]interface{}
+
+// Logger is the minimal structured logging interface every backend must
+// implement. It is deliberately small so alternative implementations
+// (zap, zerolog, the standard library's slog, ...) can be dropped in
+// without the registry or any call site knowing the difference.
+type Logger interface {
+	Debug(args ...interface{})
+	Info(args ...interface{})
+	Warn(args ...interface{})
+	Error(args ...interface{})
+
+	// With returns a derived Logger that includes fields in addition to
+	// its own, without mutating the receiver.
+	With(fields Fields) Logger
+
+	// Configure (re)applies cfg to the logger, e.g. adjusting its level.
+	// It must be safe to call repeatedly on the same logger, and must
+	// return an error rather than leave itself partially reconfigured if
+	// cfg is invalid.
+	Configure(cfg *viper.Viper) error
+}
+
+// BackendFactory constructs a Logger from a name, its base fields and the
+// viper subtree configuring it.
+type BackendFactory func(name string, fields Fields, cfg *viper.Viper) (Logger, error)
+
+// DefaultBackend is used for a logger whose config doesn't set `backend`.
+const DefaultBackend = "logrus"
+
+var (
+	backendsLock sync.RWMutex
+	backends     = map[string]BackendFactory{}
+)
+
+// RegisterBackend registers a logger backend under name so it can be
+// selected from config via `logging.<key>.backend`. It panics if name is
+// already registered, mirroring the database/sql driver registry.
+func RegisterBackend(name string, f BackendFactory) {
+	backendsLock.Lock()
+	defer backendsLock.Unlock()
+	if _, dup := backends[name]; dup {
+		panic("logging: RegisterBackend called twice for backend " + name)
+	}
+	backends[name] = f
+}
+
+func backendFactory(name string) (BackendFactory, bool) {
+	backendsLock.RLock()
+	defer backendsLock.RUnlock()
+	f, ok := backends[name]
+	return f, ok
+}
+
+// namedLogger wraps a backend Logger with the bookkeeping the registry
+// needs: the name it was registered under, the registry it belongs to and,
+// for a child materialized by GetOK, its parent.
+type namedLogger struct {
+	Logger
+	name   string
+	fields Fields
+	reg    *LoggerRegistry
+	parent *namedLogger
+}
+
+// buildLogger constructs the backend selected by cfg's `backend` key
+// (DefaultBackend when unset), returning an error if that backend rejects
+// cfg. Callers that can tolerate a misconfigured logger (e.g. initial
+// registry construction) should fall back to the default backend rather
+// than propagate the error; callers validating a Reload should not.
+func buildLogger(name string, fields Fields, cfg *viper.Viper) (Logger, error) {
+	backendName := DefaultBackend
+	if cfg.IsSet("backend") {
+		backendName = cfg.GetString("backend")
+	}
+
+	factory, ok := backendFactory(backendName)
+	if !ok {
+		return nil, fmt.Errorf("logging: no backend registered as %q", backendName)
+	}
+
+	l, err := factory(name, fields, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	if hasSamplingConfig(cfg) {
+		l = newSampledLogger(l, cfg)
+	}
+	return l, nil
+}
+
+// newNamedLogger builds the backend selected by cfg's `backend` key
+// (DefaultBackend when unset) and wraps it for the registry. A
+// misconfigured backend falls back to DefaultBackend rather than failing
+// registry construction outright.
+func newNamedLogger(name string, fields Fields, cfg *viper.Viper, parent *namedLogger) *namedLogger {
+	l, err := buildLogger(name, fields, cfg)
+	if err != nil {
+		// cfg itself may be what caused the failure (a bad level string,
+		// a sink mode missing a required key, ...), so re-running the
+		// same factory against it would just fail again. Fall back to a
+		// clean config instead of re-using cfg.
+		l = safeFallbackLogger(name, fields)
+		l.Error(fmt.Sprintf("logging: failed to initialize configured backend, falling back to %q: %v", DefaultBackend, err))
+	}
+
+	return &namedLogger{
+		Logger: l,
+		name:   name,
+		fields: fields,
+		parent: parent,
+	}
+}
+
+// safeFallbackLogger builds a DefaultBackend logger from a fresh, empty
+// config so construction can never itself fail, even when the real cfg's
+// level/sink/backend settings are what caused the original failure.
+func safeFallbackLogger(name string, fields Fields) Logger {
+	backendsLock.RLock()
+	factory := backends[DefaultBackend]
+	backendsLock.RUnlock()
+
+	l, err := factory(name, fields, viper.New())
+	if err != nil {
+		// DefaultBackend must always succeed against an empty config; if
+		// it doesn't, there's no further fallback left to reach for.
+		panic(fmt.Sprintf("logging: default backend %q failed to initialize even with an empty config: %v", DefaultBackend, err))
+	}
+	return l
+}
+
+// addLoggingDefaults fills in the config keys NewRegistry and
+// newNamedLogger rely on being set so every logger subtree can be treated
+// uniformly regardless of what the user actually wrote.
+func addLoggingDefaults(cfg *viper.Viper) {
+	cfg.SetDefault("backend", DefaultBackend)
+	cfg.SetDefault("level", "info")
+}
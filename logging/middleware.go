@@ -0,0 +1,26 @@
+package logging
+
+import "net/http"
+
+// DefaultRequestIDHeader is the header Middleware reads a request ID from
+// when no header is configured.
+const DefaultRequestIDHeader = "X-Request-ID"
+
+// Middleware returns net/http middleware that derives a request-scoped
+// logger from base (e.g. a registry's root logger, which already carries
+// any field set via LoggerRegistry.WithVersion), stamps it with a request
+// ID read from header (falling back to DefaultRequestIDHeader), and
+// stores the result in the request's context for downstream handlers to
+// retrieve with FromContext.
+func Middleware(base Logger, header string) func(http.Handler) http.Handler {
+	if header == "" {
+		header = DefaultRequestIDHeader
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			l := base.With(Fields{"request_id": req.Header.Get(header)})
+			next.ServeHTTP(w, req.WithContext(WithLogger(req.Context(), l)))
+		})
+	}
+}
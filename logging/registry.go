@@ -1,14 +1,21 @@
 package logging
 
 import (
+	"context"
+	"fmt"
 	"sort"
 	"strings"
 	"sync"
+	"time"
 
-	"github.com/Sirupsen/logrus"
+	"github.com/fsnotify/fsnotify"
 	"github.com/spf13/viper"
 )
 
+// defaultWatchDebounce coalesces the burst of writes many editors make
+// when saving a single file into one Reload.
+const defaultWatchDebounce = 250 * time.Millisecond
+
 // RootName of the root logger, defaults to root
 var RootName string
 
@@ -18,29 +25,48 @@ func init() {
 
 // LoggerRegistry represents a registry for known loggers
 type LoggerRegistry struct {
-	config *viper.Viper
+	root   *viper.Viper // the original, file-backed config Watch listens on
+	config *viper.Viper // the "logging" subtree currently in use, derived from root
 	store  map[string]Logger
 	lock   *sync.Mutex
+
+	onReloadError func(error)
+	watchDebounce time.Duration
+	version       string
 }
 
-// NewRegistry creates a new logger registry
-func NewRegistry(cfg *viper.Viper, context logrus.Fields) *LoggerRegistry {
-	c := cfg
-	if c.InConfig("logging") {
-		c = cfg.Sub("logging")
+// loggingSubtree derives the "logging" subtree from root the same way at
+// construction and on every Reload, so a Reload driven by Watch re-reads
+// root's current (possibly just rewritten) values instead of reusing a
+// stale snapshot.
+func loggingSubtree(root *viper.Viper) *viper.Viper {
+	if root.InConfig("logging") {
+		return root.Sub("logging")
 	}
+	return root
+}
+
+// NewRegistry creates a new logger registry. context is a set of fields
+// applied to every logger NewRegistry constructs, e.g. service name or
+// environment, and inherited by children GetOK materializes under them.
+// The registry does not keep context itself, so a logger built for an
+// entry passed straight to Register does not carry it.
+func NewRegistry(cfg *viper.Viper, context Fields) *LoggerRegistry {
+	c := loggingSubtree(cfg)
 
 	keys := c.AllKeys()
 	store := make(map[string]Logger, len(keys))
 	reg := &LoggerRegistry{
-		store:  store,
-		config: c,
-		lock:   new(sync.Mutex),
+		root:          cfg,
+		store:         store,
+		config:        c,
+		lock:          new(sync.Mutex),
+		watchDebounce: defaultWatchDebounce,
 	}
 
 	for _, k := range keys {
 		// no sharing of context, so copy
-		fields := make(logrus.Fields, len(context)+1)
+		fields := make(Fields, len(context)+1)
 		for kk, vv := range context {
 			fields[kk] = vv
 		}
@@ -61,7 +87,7 @@ func NewRegistry(cfg *viper.Viper, context logrus.Fields) *LoggerRegistry {
 		reg.store[k] = l
 	}
 	if len(keys) == 0 {
-		fields := make(logrus.Fields, len(context)+1)
+		fields := make(Fields, len(context)+1)
 		for k, v := range context {
 			fields[k] = v
 		}
@@ -85,12 +111,79 @@ func (r *LoggerRegistry) Get(name string) Logger {
 	return l
 }
 
-// GetOK a logger by name, boolean is true when a logger was found
+// GetOK looks up a logger by name. A dotted path that has no exact entry
+// is resolved hierarchically, Python-logging style: "a.b.c" falls back to
+// the longest registered ancestor ("a.b", then "a", then RootName). The
+// resulting child is materialized inheriting its parent's fields and
+// cached in the store, so the walk only happens on first use and
+// subsequent lookups are a single map read.
 func (r *LoggerRegistry) GetOK(name string) (Logger, bool) {
+	name = strings.ToLower(name)
+
 	r.lock.Lock()
-	res, ok := r.store[strings.ToLower(name)]
-	r.lock.Unlock()
-	return res, ok
+	defer r.lock.Unlock()
+	return r.getOrCreateLocked(name)
+}
+
+// getOrCreateLocked implements GetOK's hierarchical lookup; callers must
+// hold r.lock.
+func (r *LoggerRegistry) getOrCreateLocked(name string) (Logger, bool) {
+	if l, ok := r.store[name]; ok {
+		return l, true
+	}
+
+	parentName, ok := longestStoredPrefix(name, r.store)
+	if !ok {
+		return nil, false
+	}
+
+	// parentName may have been registered directly via Register with a
+	// Logger that isn't a *namedLogger (no fields/parent bookkeeping of
+	// its own), so start from just the module field (plus the registry's
+	// version, if set) rather than assume the parent looks like one we
+	// built; such a child does not carry NewRegistry's context fields.
+	parent, ok := r.store[parentName].(*namedLogger)
+	fields := Fields{"module": name}
+	if ok {
+		for k, v := range parent.fields {
+			fields[k] = v
+		}
+	}
+	fields["module"] = name
+	fields = r.seedVersion(fields)
+
+	cfg := findLongestMatchingPath(name, r.config)
+	if cfg == nil {
+		// No ancestor has its own config subtree; fall back to the
+		// registry's settings, but on a detached copy so the
+		// SetDefault calls below don't mutate the shared r.config as a
+		// side effect of what's meant to be a read-only Get.
+		cfg = viper.New()
+		_ = cfg.MergeConfigMap(r.config.AllSettings())
+	}
+	addLoggingDefaults(cfg)
+
+	l := newNamedLogger(name, fields, cfg, parent)
+	l.reg = r
+	r.store[name] = l
+	return l, true
+}
+
+// longestStoredPrefix finds the longest strict dotted prefix of name that
+// already has an entry in store, falling back to RootName so any dotted
+// path resolves to something as long as a root logger was registered.
+func longestStoredPrefix(name string, store map[string]Logger) (string, bool) {
+	parts := strings.Split(name, ".")
+	for i := len(parts) - 1; i > 0; i-- {
+		k := strings.Join(parts[:i], ".")
+		if _, ok := store[k]; ok {
+			return k, true
+		}
+	}
+	if _, ok := store[RootName]; ok {
+		return RootName, true
+	}
+	return "", false
 }
 
 // Register a logger in this registry, overrides existing keys
@@ -105,11 +198,161 @@ func (r *LoggerRegistry) Root() Logger {
 	return r.Get(RootName)
 }
 
-// Reload all the loggers with the new config
-func (r *LoggerRegistry) Reload() {
+// WithVersion seeds a "version" field applied to every logger this
+// registry constructs from this point on (hierarchical auto-materialized
+// children, and loggers rebuilt by Reload), and stamps it onto every
+// existing *namedLogger in the store; any other Logger passed to
+// Register is left as it is. This is how a build's version ends up on
+// every log line without handlers threading it through explicitly; pair
+// it with Middleware/FromContext to add request-scoped fields on top.
+func (r *LoggerRegistry) WithVersion(v string) *LoggerRegistry {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.version = v
+	for _, l := range r.store {
+		nl, ok := l.(*namedLogger)
+		if !ok {
+			continue
+		}
+		nl.fields["version"] = v
+		nl.Logger = nl.Logger.With(Fields{"version": v})
+	}
+	return r
+}
+
+// seedVersion overlays the registry's version field, if set, onto fields
+// built for a newly constructed or rebuilt logger.
+func (r *LoggerRegistry) seedVersion(fields Fields) Fields {
+	if r.version != "" {
+		fields["version"] = r.version
+	}
+	return fields
+}
+
+// SinkDescriber is implemented by backends whose Logger exposes its
+// active sink's configuration.
+type SinkDescriber interface {
+	SinkDescription() string
+}
+
+// GetLogDescriptions returns the active sink configuration for every
+// registered logger, keyed by name, so an admin HTTP handler can render
+// the current logging topology.
+func (r *LoggerRegistry) GetLogDescriptions() map[string]string {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	out := make(map[string]string, len(r.store))
+	for name, l := range r.store {
+		backend := l
+		if nl, ok := l.(*namedLogger); ok {
+			backend = nl.Logger
+		}
+		if sl, ok := backend.(*sampledLogger); ok {
+			backend = sl.Logger
+		}
+
+		if d, ok := backend.(SinkDescriber); ok {
+			out[name] = d.SinkDescription()
+			continue
+		}
+		out[name] = "unknown"
+	}
+	return out
+}
+
+// Stats returns the emitted/dropped sampling counters for every logger
+// that has sampling or rate limiting configured (see SampleStats), keyed
+// by name, for observability into how much a noisy logger is being
+// throttled.
+func (r *LoggerRegistry) Stats() map[string]SampleStats {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	out := make(map[string]SampleStats)
+	for name, l := range r.store {
+		if nl, ok := l.(*namedLogger); ok {
+			l = nl.Logger
+		}
+		if s, ok := l.(*sampledLogger); ok {
+			out[name] = s.stats()
+		}
+	}
+	return out
+}
+
+// OnReloadError registers a callback invoked whenever a Reload triggered
+// by Watch fails validation. The registry's existing loggers are left
+// untouched when this fires. It is not called for a manual Reload, whose
+// caller already has the returned error.
+func (r *LoggerRegistry) OnReloadError(f func(error)) {
+	r.lock.Lock()
+	r.onReloadError = f
+	r.lock.Unlock()
+}
+
+// Watch hooks the registry into viper's file watcher so edits to the
+// logging config are picked up without an explicit Reload call. It
+// watches root — the original, file-backed config — rather than the
+// detached "logging" subtree, since a viper Sub() has no file of its own
+// and never fires OnConfigChange. Rapid successive writes (editors
+// commonly save a file more than once) are coalesced into a single
+// Reload per debounce window. Watch returns immediately; it stops
+// watching once ctx is canceled.
+func (r *LoggerRegistry) Watch(ctx context.Context) {
+	var (
+		mu    sync.Mutex
+		timer *time.Timer
+	)
+
+	r.root.OnConfigChange(func(_ fsnotify.Event) {
+		mu.Lock()
+		defer mu.Unlock()
+		if ctx.Err() != nil {
+			return
+		}
+		if timer != nil {
+			timer.Stop()
+		}
+		timer = time.AfterFunc(r.watchDebounce, func() {
+			if err := r.Reload(); err != nil {
+				r.lock.Lock()
+				onErr := r.onReloadError
+				r.lock.Unlock()
+				if onErr != nil {
+					onErr(err)
+				}
+			}
+		})
+	})
+	r.root.WatchConfig()
+
+	go func() {
+		<-ctx.Done()
+		mu.Lock()
+		if timer != nil {
+			timer.Stop()
+		}
+		mu.Unlock()
+	}()
+}
+
+// Reload re-derives the "logging" subtree from root — picking up any
+// rewrite Watch noticed — and re-validates against it every logger whose
+// name (or a dotted ancestor of it) has a config subtree there, swapping
+// in the new backends atomically: a new Logger is built for each such
+// entry first, and only if every single one accepts its config are they
+// committed, including the new subtree replacing r.config. A logger with
+// no matching subtree is left as it is. If any
+// logger rejects its new config, none of them are touched, the previous
+// configuration is kept intact, and the rejection is returned.
+func (r *LoggerRegistry) Reload() error {
 	r.lock.Lock()
 	defer r.lock.Unlock()
 
+	newConfig := loggingSubtree(r.root)
+
 	// Get all keys, sorted by name and shortest to longest
 	var keys []string
 	for key := range r.store {
@@ -120,18 +363,78 @@ func (r *LoggerRegistry) Reload() {
 	// find matching config
 	// for each key find the longest possible path that has a config
 	// if no more path found or parts are exhausted use last config and stop searching
-	configs := make(map[string]*viper.Viper, len(keys))
-	for _, key := range keys {
-		configs[key] = findLongestMatchingPath(key, r.config)
+	replacements := make(map[string]Logger, len(keys))
+	for _, k := range keys {
+		cfg := findLongestMatchingPath(k, newConfig)
+		if cfg == nil {
+			continue
+		}
+		addLoggingDefaults(cfg)
+
+		fields := Fields{"module": k}
+		if nl, ok := r.store[k].(*namedLogger); ok {
+			fields = nl.fields
+		}
+		fields = r.seedVersion(fields)
+
+		l, err := buildLogger(k, fields, cfg)
+		if err != nil {
+			// Every candidate built so far already opened a real sink
+			// (file, syslog, journald, ...); close them before bailing
+			// out or a rejected reload leaks them.
+			for _, built := range replacements {
+				closeSink(built)
+			}
+			return fmt.Errorf("logging: reload rejected, logger %q would not accept new config: %w", k, err)
+		}
+
+		// Reload rebuilds a fresh sampledLogger rather than reconfiguring
+		// the existing one in place (so a rejected candidate elsewhere
+		// can't leave this one half-applied); carry its emitted/dropped
+		// counts forward so Stats() isn't reset by every reload.
+		if newSampled, ok := l.(*sampledLogger); ok {
+			if nl, ok := r.store[k].(*namedLogger); ok {
+				if oldSampled, ok := nl.Logger.(*sampledLogger); ok {
+					newSampled.transferStats(oldSampled)
+				}
+			}
+		}
+
+		replacements[k] = l
 	}
 
-	// call reconfigure on logger
-	for _, k := range keys {
-		logger := r.store[k]
-		if cfg, ok := configs[k]; ok {
-			logger.Configure(cfg)
+	// every candidate built cleanly, so it's safe to commit the swap
+	for k, l := range replacements {
+		if nl, ok := r.store[k].(*namedLogger); ok {
+			old := nl.Logger
+			nl.Logger = l
+			closeSink(old)
+		} else {
+			r.store[k] = l
 		}
 	}
+	r.config = newConfig
+	return nil
+}
+
+// sinkCloser is implemented by backends that hold a Sink, letting Reload
+// release it after superseding it with a freshly built logger.
+type sinkCloser interface {
+	closeSink()
+}
+
+// closeSink releases the Sink held by l's backend, if any, unwrapping a
+// sampledLogger first. It's called on the logger a Reload just
+// superseded, since Reload swaps in a whole new Logger rather than
+// reconfiguring the existing one, which would otherwise leak the old
+// sink (e.g. a rotating file's descriptor).
+func closeSink(l Logger) {
+	if sl, ok := l.(*sampledLogger); ok {
+		l = sl.Logger
+	}
+	if c, ok := l.(sinkCloser); ok {
+		c.closeSink()
+	}
 }
 
 func findLongestMatchingPath(path string, cfg *viper.Viper) *viper.Viper {
@@ -0,0 +1,85 @@
+package logging
+
+import (
+	"testing"
+
+	"github.com/spf13/viper"
+)
+
+// newEmptyRegistry builds a registry from an empty, flat (no "logging"
+// prefix) config, so only RootName is auto-registered and tests can seed
+// the rest of the hierarchy explicitly via Register.
+func newEmptyRegistry() *LoggerRegistry {
+	return NewRegistry(viper.New(), nil)
+}
+
+func TestGetOKHierarchicalResolution(t *testing.T) {
+	reg := newEmptyRegistry()
+	reg.Register("api", newNamedLogger("api", Fields{"module": "api"}, viper.New(), nil))
+
+	child, ok := reg.GetOK("api.handlers")
+	if !ok || child == nil {
+		t.Fatalf("expected api.handlers to resolve under the registered api logger")
+	}
+
+	// Resolving the same dotted path again should hit the cached entry.
+	again, ok := reg.GetOK("api.handlers")
+	if !ok || again != child {
+		t.Fatalf("expected second GetOK to return the cached child logger")
+	}
+}
+
+func TestGetOKParentRegisteredDirectlyIsNotNamedLogger(t *testing.T) {
+	reg := newEmptyRegistry()
+
+	// A logger registered directly via Register has no fields/parent
+	// bookkeeping of its own; getOrCreateLocked must not assume it can be
+	// asserted to *namedLogger when resolving a child under it.
+	reg.Register("custom", safeFallbackLogger("custom", Fields{"module": "custom"}))
+
+	child, ok := reg.GetOK("custom.sub")
+	if !ok || child == nil {
+		t.Fatalf("expected custom.sub to resolve under the directly-registered parent without panicking")
+	}
+}
+
+func TestGetOKFallbackDoesNotMutateSharedConfig(t *testing.T) {
+	reg := newEmptyRegistry()
+
+	if reg.config.IsSet("backend") || reg.config.IsSet("level") {
+		t.Fatalf("registry config already has defaults set before GetOK; test setup invalid")
+	}
+
+	if _, ok := reg.GetOK("anything"); !ok {
+		t.Fatalf("expected anything to resolve under RootName")
+	}
+
+	if reg.config.IsSet("backend") || reg.config.IsSet("level") {
+		t.Fatalf("getOrCreateLocked's addLoggingDefaults mutated the shared registry config")
+	}
+}
+
+func TestReloadKeepsOldConfigOnValidationFailure(t *testing.T) {
+	cfg := viper.New()
+	reg := newEmptyRegistry()
+
+	cfg.Set("bad.level", "info")
+	reg.Register("bad", newNamedLogger("bad", Fields{"module": "bad"}, findLongestMatchingPath("bad", cfg), nil))
+
+	// Swap in cfg as the watched root/config so Reload re-derives from it.
+	reg.root = cfg
+	reg.config = cfg
+	before := reg.config
+
+	// "not-a-real-level" is rejected by logrus.ParseLevel, so the rebuilt
+	// logger for "bad" fails and the whole reload must roll back, leaving
+	// reg.config untouched.
+	cfg.Set("bad.level", "not-a-real-level")
+
+	if err := reg.Reload(); err == nil {
+		t.Fatalf("expected Reload to reject an invalid level, got nil error")
+	}
+	if reg.config != before {
+		t.Fatalf("expected reg.config to be left untouched after a rejected Reload")
+	}
+}
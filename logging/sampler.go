@@ -0,0 +1,179 @@
+package logging
+
+import (
+	"sync"
+	"time"
+
+	"github.com/spf13/viper"
+	"golang.org/x/time/rate"
+)
+
+// SampleStats holds the emitted/dropped counters for a single logger's
+// sampler, returned from LoggerRegistry.Stats.
+type SampleStats struct {
+	Emitted uint64
+	Dropped uint64
+}
+
+// hasSamplingConfig reports whether cfg sets any of the keys that cause
+// buildLogger to wrap a logger in a sampledLogger.
+func hasSamplingConfig(cfg *viper.Viper) bool {
+	return cfg.IsSet("sample.initial") || cfg.IsSet("sample.thereafter") ||
+		cfg.IsSet("sample.interval") || cfg.IsSet("rate_limit.per_second")
+}
+
+// sampleState holds a sampledLogger's mutable sampling state. It is held
+// by pointer and shared across every Logger With derives from the same
+// original sampledLogger, so a request-scoped logger handed out by
+// Middleware/FromContext samples against (and reports stats for) the same
+// window and counters as the logger it was derived from, rather than
+// starting a fresh "first N" window and invisible counters every request.
+type sampleState struct {
+	mu         sync.Mutex
+	initial    uint64
+	thereafter uint64
+	interval   time.Duration
+	windowEnd  time.Time
+	count      uint64
+	limiter    *rate.Limiter
+	emitted    uint64
+	dropped    uint64
+}
+
+// sampledLogger wraps a Logger with the "emit the first N events per
+// interval, then 1-in-M thereafter" scheme, an optional token-bucket rate
+// limit, or both, so a noisy logger can't drown out everything else or
+// burn through IOPS during a spike. Applied by buildLogger when a
+// logger's config sets any sample.* or rate_limit.* key.
+type sampledLogger struct {
+	Logger
+	state *sampleState
+}
+
+func newSampledLogger(inner Logger, cfg *viper.Viper) *sampledLogger {
+	s := &sampledLogger{Logger: inner, state: &sampleState{}}
+	s.applySampleConfig(cfg)
+	return s
+}
+
+// applySampleConfig resets the sampling window and rate limiter from cfg.
+// It is used by both construction and Configure and takes s.state.mu
+// itself, so the new parameters are swapped in atomically with respect
+// to concurrent logging.
+func (s *sampledLogger) applySampleConfig(cfg *viper.Viper) {
+	st := s.state
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	st.initial = uint64(cfg.GetInt("sample.initial"))
+	st.thereafter = uint64(cfg.GetInt("sample.thereafter"))
+	st.interval = cfg.GetDuration("sample.interval")
+	if st.interval <= 0 {
+		st.interval = time.Second
+	}
+	st.windowEnd = time.Time{}
+	st.count = 0
+
+	st.limiter = nil
+	if perSecond := cfg.GetFloat64("rate_limit.per_second"); perSecond > 0 {
+		st.limiter = rate.NewLimiter(rate.Limit(perSecond), int(perSecond)+1)
+	}
+}
+
+// allow reports whether the event currently being logged should pass
+// through to the wrapped Logger, bumping the emitted/dropped counters
+// either way.
+func (s *sampledLogger) allow() bool {
+	st := s.state
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	now := time.Now()
+	if now.After(st.windowEnd) {
+		st.windowEnd = now.Add(st.interval)
+		st.count = 0
+	}
+	st.count++
+
+	// No sample.* keys set means sampling itself isn't in play for this
+	// logger (e.g. rate_limit.per_second used standalone); only the token
+	// bucket below should gate in that case.
+	sampled := st.initial == 0 && st.thereafter == 0
+	if !sampled {
+		sampled = st.count <= st.initial || (st.thereafter > 0 && (st.count-st.initial)%st.thereafter == 0)
+	}
+	if !sampled || (st.limiter != nil && !st.limiter.Allow()) {
+		st.dropped++
+		return false
+	}
+	st.emitted++
+	return true
+}
+
+func (s *sampledLogger) Debug(args ...interface{}) {
+	if s.allow() {
+		s.Logger.Debug(args...)
+	}
+}
+
+func (s *sampledLogger) Info(args ...interface{}) {
+	if s.allow() {
+		s.Logger.Info(args...)
+	}
+}
+
+func (s *sampledLogger) Warn(args ...interface{}) {
+	if s.allow() {
+		s.Logger.Warn(args...)
+	}
+}
+
+func (s *sampledLogger) Error(args ...interface{}) {
+	if s.allow() {
+		s.Logger.Error(args...)
+	}
+}
+
+// With returns a derived sampledLogger that shares this logger's
+// sampleState, so the window, counters and rate limiter it samples
+// against are the same ones the original logger (and every other logger
+// derived from it) uses.
+func (s *sampledLogger) With(fields Fields) Logger {
+	return &sampledLogger{
+		Logger: s.Logger.With(fields),
+		state:  s.state,
+	}
+}
+
+func (s *sampledLogger) Configure(cfg *viper.Viper) error {
+	if err := s.Logger.Configure(cfg); err != nil {
+		return err
+	}
+	s.applySampleConfig(cfg)
+	return nil
+}
+
+func (s *sampledLogger) stats() SampleStats {
+	st := s.state
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	return SampleStats{Emitted: st.emitted, Dropped: st.dropped}
+}
+
+// transferStats carries old's emitted/dropped counts forward onto s. Used
+// when Reload rebuilds a fresh sampledLogger in place of an existing one,
+// so a config reload doesn't reset the counters Stats() reports.
+func (s *sampledLogger) transferStats(old *sampledLogger) {
+	if old == nil {
+		return
+	}
+
+	old.state.mu.Lock()
+	emitted, dropped := old.state.emitted, old.state.dropped
+	old.state.mu.Unlock()
+
+	s.state.mu.Lock()
+	s.state.emitted += emitted
+	s.state.dropped += dropped
+	s.state.mu.Unlock()
+}
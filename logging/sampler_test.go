@@ -0,0 +1,102 @@
+package logging
+
+import (
+	"testing"
+
+	"github.com/spf13/viper"
+)
+
+type countingLogger struct {
+	logged int
+}
+
+func (c *countingLogger) Debug(args ...interface{}) { c.logged++ }
+func (c *countingLogger) Info(args ...interface{})  { c.logged++ }
+func (c *countingLogger) Warn(args ...interface{})  { c.logged++ }
+func (c *countingLogger) Error(args ...interface{}) { c.logged++ }
+func (c *countingLogger) With(fields Fields) Logger { return c }
+
+func (c *countingLogger) Configure(cfg *viper.Viper) error { return nil }
+
+func TestSampledLoggerFirstNThenOneInM(t *testing.T) {
+	inner := &countingLogger{}
+	cfg := viper.New()
+	cfg.Set("sample.initial", 2)
+	cfg.Set("sample.thereafter", 3)
+	cfg.Set("sample.interval", "1h")
+
+	s := newSampledLogger(inner, cfg)
+
+	// First two events within the window pass straight through.
+	s.Info("a")
+	s.Info("b")
+	if inner.logged != 2 {
+		t.Fatalf("expected the first 2 events to pass, got %d", inner.logged)
+	}
+
+	// Events 3 and 4 are dropped; event 5 is the next 1-in-3 sample
+	// ((5-initial) % thereafter == (5-2) % 3 == 0).
+	s.Info("c")
+	s.Info("d")
+	if inner.logged != 2 {
+		t.Fatalf("expected events 3 and 4 to be dropped, got %d logged", inner.logged)
+	}
+	s.Info("e")
+	if inner.logged != 3 {
+		t.Fatalf("expected the 5th event (1-in-3 after the initial 2) to pass, got %d logged", inner.logged)
+	}
+	s.Info("f")
+	if inner.logged != 3 {
+		t.Fatalf("expected the 6th event to still be dropped, got %d logged", inner.logged)
+	}
+
+	stats := s.stats()
+	if stats.Emitted != 3 || stats.Dropped != 3 {
+		t.Fatalf("expected 3 emitted and 3 dropped, got %+v", stats)
+	}
+}
+
+func TestSampledLoggerRateLimitAloneGates(t *testing.T) {
+	inner := &countingLogger{}
+	cfg := viper.New()
+	cfg.Set("rate_limit.per_second", 1)
+
+	s := newSampledLogger(inner, cfg)
+
+	// With no sample.* keys set, sampling itself must not gate events;
+	// only the token bucket should, so the first event (the bucket's
+	// initial burst) passes.
+	s.Info("a")
+	if inner.logged != 1 {
+		t.Fatalf("expected rate limiting alone to allow the first event, got %d logged", inner.logged)
+	}
+}
+
+func TestSampledLoggerWithSharesState(t *testing.T) {
+	inner := &countingLogger{}
+	cfg := viper.New()
+	cfg.Set("sample.initial", 1)
+	cfg.Set("sample.thereafter", 0)
+	cfg.Set("sample.interval", "1h")
+
+	s := newSampledLogger(inner, cfg)
+	derived := s.With(Fields{"request_id": "abc"})
+
+	// The first event on the parent consumes the "first 1" allowance.
+	s.Info("a")
+	if inner.logged != 1 {
+		t.Fatalf("expected the first event on the parent to pass, got %d logged", inner.logged)
+	}
+
+	// A logger derived via With (as Middleware/FromContext do per request)
+	// must share the same sampling window, not start a fresh "first N".
+	derived.Info("b")
+	if inner.logged != 1 {
+		t.Fatalf("expected the derived logger's event to be sampled against the shared window, got %d logged", inner.logged)
+	}
+
+	stats := s.stats()
+	if stats.Emitted != 1 || stats.Dropped != 1 {
+		t.Fatalf("expected derived logger's drop to be visible via the parent's stats, got %+v", stats)
+	}
+}
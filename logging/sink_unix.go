@@ -0,0 +1,59 @@
+//go:build !windows
+
+package logging
+
+import (
+	"fmt"
+	"log/syslog"
+
+	"github.com/coreos/go-systemd/journal"
+	"github.com/spf13/viper"
+)
+
+// syslog and journald have no meaningful equivalent on Windows
+// (log/syslog doesn't build there at all), so these modes are only
+// registered on unix-like platforms; a config requesting them on Windows
+// falls through to buildSink's "no sink registered for mode" error.
+func init() {
+	RegisterSink("syslog", newSyslogSink)
+	RegisterSink("journald", newJournaldSink)
+}
+
+// syslogSink forwards to the local syslog daemon under the configured
+// tag, always at the daemon facility and info priority.
+type syslogSink struct {
+	*syslog.Writer
+	tag string
+}
+
+func newSyslogSink(cfg *viper.Viper) (Sink, error) {
+	tag := cfg.GetString("syslog.tag")
+	w, err := syslog.New(syslog.LOG_INFO|syslog.LOG_DAEMON, tag)
+	if err != nil {
+		return nil, fmt.Errorf("syslog sink: %w", err)
+	}
+	return &syslogSink{Writer: w, tag: tag}, nil
+}
+
+func (s *syslogSink) Describe() string { return fmt.Sprintf("syslog (tag=%s)", s.tag) }
+
+// journaldSink forwards to the systemd journal, which handles its own
+// rotation, so there's nothing to configure beyond availability.
+type journaldSink struct{}
+
+func newJournaldSink(cfg *viper.Viper) (Sink, error) {
+	if !journal.Enabled() {
+		return nil, fmt.Errorf("mode \"journald\" requested but journald is not available")
+	}
+	return journaldSink{}, nil
+}
+
+func (journaldSink) Write(p []byte) (int, error) {
+	if err := journal.Send(string(p), journal.PriInfo, nil); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+func (journaldSink) Close() error     { return nil }
+func (journaldSink) Describe() string { return "journald" }
@@ -0,0 +1,71 @@
+package logging
+
+import (
+	"fmt"
+	"os"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/spf13/viper"
+)
+
+func init() {
+	RegisterBackend("zap", newZapLogger)
+}
+
+// zapLogger is an opt-in Logger backend for deployments that want zap's
+// allocation profile instead of logrus'. Select it per-logger with
+// `backend: zap` in that logger's config subtree.
+type zapLogger struct {
+	level *zap.AtomicLevel
+	sugar *zap.SugaredLogger
+}
+
+func newZapLogger(name string, fields Fields, cfg *viper.Viper) (Logger, error) {
+	level := zap.NewAtomicLevel()
+	core := zapcore.NewCore(
+		zapcore.NewJSONEncoder(zap.NewProductionEncoderConfig()),
+		zapcore.Lock(zapcore.AddSync(os.Stderr)),
+		level,
+	)
+
+	l := &zapLogger{
+		level: &level,
+		sugar: zap.New(core).Sugar().With(fieldsToArgs(fields)...),
+	}
+	if err := l.Configure(cfg); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+func (l *zapLogger) Debug(args ...interface{}) { l.sugar.Debug(args...) }
+func (l *zapLogger) Info(args ...interface{})  { l.sugar.Info(args...) }
+func (l *zapLogger) Warn(args ...interface{})  { l.sugar.Warn(args...) }
+func (l *zapLogger) Error(args ...interface{}) { l.sugar.Error(args...) }
+
+func (l *zapLogger) With(fields Fields) Logger {
+	return &zapLogger{level: l.level, sugar: l.sugar.With(fieldsToArgs(fields)...)}
+}
+
+func (l *zapLogger) Configure(cfg *viper.Viper) error {
+	level := zapcore.InfoLevel
+	if cfg.IsSet("level") {
+		parsed, err := zapcore.ParseLevel(cfg.GetString("level"))
+		if err != nil {
+			return fmt.Errorf("logging: invalid zap level %q: %w", cfg.GetString("level"), err)
+		}
+		level = parsed
+	}
+	l.level.SetLevel(level)
+	return nil
+}
+
+func fieldsToArgs(fields Fields) []interface{} {
+	args := make([]interface{}, 0, len(fields)*2)
+	for k, v := range fields {
+		args = append(args, k, v)
+	}
+	return args
+}